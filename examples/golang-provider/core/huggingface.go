@@ -0,0 +1,101 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// huggingfaceDefaultBaseURL is the public HuggingFace Inference API.
+const huggingfaceDefaultBaseURL = "https://api-inference.huggingface.co"
+
+// HuggingFaceBackend calls the HuggingFace Inference API for a single model.
+type HuggingFaceBackend struct {
+	apiKey  string
+	baseURL string
+	modelID string
+}
+
+// NewHuggingFaceBackend builds a HuggingFaceBackend from a provider config map,
+// falling back to the HUGGINGFACE_API_KEY environment variable when
+// config["apiKey"] isn't set.
+func NewHuggingFaceBackend(config map[string]interface{}) (Backend, error) {
+	apiKey := configString(config, "apiKey", os.Getenv("HUGGINGFACE_API_KEY"))
+	if apiKey == "" {
+		return nil, fmt.Errorf("huggingface backend requires an apiKey (config or HUGGINGFACE_API_KEY)")
+	}
+
+	modelID := configString(config, "modelId", "")
+	if modelID == "" {
+		return nil, fmt.Errorf("huggingface backend requires a modelId")
+	}
+
+	return &HuggingFaceBackend{
+		apiKey:  apiKey,
+		baseURL: configString(config, "baseUrl", huggingfaceDefaultBaseURL),
+		modelID: modelID,
+	}, nil
+}
+
+type huggingfaceRequest struct {
+	Inputs string `json:"inputs"`
+}
+
+type huggingfaceGeneration struct {
+	GeneratedText string `json:"generated_text"`
+}
+
+// CreateCompletion implements Backend by POSTing to
+// /models/{modelId} on the HuggingFace Inference API.
+func (b *HuggingFaceBackend) CreateCompletion(ctx context.Context, prompt string, opts CompletionOptions) (*CompletionResult, error) {
+	modelID := opts.ModelID
+	if modelID == "" {
+		modelID = b.modelID
+	}
+
+	body, err := json.Marshal(huggingfaceRequest{Inputs: prompt})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling huggingface request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/models/"+modelID, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building huggingface request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("huggingface request error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading huggingface response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("huggingface request failed with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var generations []huggingfaceGeneration
+	if err := json.Unmarshal(respBody, &generations); err != nil {
+		return nil, fmt.Errorf("error parsing huggingface response: %v", err)
+	}
+	if len(generations) == 0 {
+		return nil, fmt.Errorf("huggingface response contained no generations")
+	}
+
+	return &CompletionResult{
+		Output: generations[0].GeneratedText,
+	}, nil
+}
+
+func init() {
+	RegisterBackendFactory("huggingface", NewHuggingFaceBackend)
+}
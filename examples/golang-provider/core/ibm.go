@@ -0,0 +1,111 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ibmDefaultBaseURL is watsonx.ai's public endpoint; override via config["baseUrl"]
+// for a dedicated or regional deployment.
+const ibmDefaultBaseURL = "https://us-south.ml.cloud.ibm.com"
+
+// IBMBackend calls IBM watsonx.ai's text generation endpoint.
+type IBMBackend struct {
+	apiKey  string
+	baseURL string
+	modelID string
+}
+
+// NewIBMBackend builds an IBMBackend from a provider config map, falling back to
+// the IBM_API_KEY environment variable when config["apiKey"] isn't set.
+func NewIBMBackend(config map[string]interface{}) (Backend, error) {
+	apiKey := configString(config, "apiKey", os.Getenv("IBM_API_KEY"))
+	if apiKey == "" {
+		return nil, fmt.Errorf("ibm backend requires an apiKey (config or IBM_API_KEY)")
+	}
+
+	return &IBMBackend{
+		apiKey:  apiKey,
+		baseURL: configString(config, "baseUrl", ibmDefaultBaseURL),
+		modelID: configString(config, "modelId", ""),
+	}, nil
+}
+
+type ibmGenerationRequest struct {
+	ModelID string `json:"model_id"`
+	Input   string `json:"input"`
+}
+
+type ibmGenerationResponse struct {
+	Results []struct {
+		GeneratedText   string `json:"generated_text"`
+		GeneratedTokens int    `json:"generated_token_count"`
+		InputTokenCount int    `json:"input_token_count"`
+		StopReason      string `json:"stop_reason"`
+	} `json:"results"`
+}
+
+// CreateCompletion implements Backend by POSTing to watsonx.ai's
+// /ml/v1/text/generation endpoint.
+func (b *IBMBackend) CreateCompletion(ctx context.Context, prompt string, opts CompletionOptions) (*CompletionResult, error) {
+	modelID := opts.ModelID
+	if modelID == "" {
+		modelID = b.modelID
+	}
+
+	body, err := json.Marshal(ibmGenerationRequest{ModelID: modelID, Input: prompt})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling watsonx request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/ml/v1/text/generation", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building watsonx request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("watsonx request error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading watsonx response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("watsonx request failed with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var parsed ibmGenerationResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing watsonx response: %v", err)
+	}
+	if len(parsed.Results) == 0 {
+		return nil, fmt.Errorf("watsonx response contained no results")
+	}
+
+	result := parsed.Results[0]
+	usage := TokenUsage{
+		PromptTokens:     result.InputTokenCount,
+		CompletionTokens: result.GeneratedTokens,
+		TotalTokens:      result.InputTokenCount + result.GeneratedTokens,
+	}
+
+	return &CompletionResult{
+		Output:       result.GeneratedText,
+		TokenUsage:   usage,
+		FinishReason: result.StopReason,
+	}, nil
+}
+
+func init() {
+	RegisterBackendFactory("ibm", NewIBMBackend)
+}
@@ -3,13 +3,22 @@ package core
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/promptfoo/promptfoo/examples/golang-provider/pkg1"
 	"github.com/sashabaranov/go-openai"
 )
 
+// maxToolCallRounds bounds CreateCompletionWithTools' tool-calling loop. Without
+// a cap, a model (or a tool result) that keeps provoking another "tool_calls"
+// response loops forever unless a timeout happens to be configured.
+const maxToolCallRounds = 10
+
 // Client wraps the OpenAI API client with custom functionality for reasoning control.
 // It provides a simplified interface for making chat completion requests with
 // configurable reasoning effort levels.
@@ -25,18 +34,63 @@ func NewClient() *Client {
 	}
 }
 
-// CreateCompletion generates a chat completion with reasoning effort control.
-// It takes a prompt string and a reasoningEffort level ("low", "medium", "high")
-// and returns the model's response as a string.
-//
-// The reasoning effort parameter controls how much computation the model spends
-// on analyzing and solving the problem. Higher effort may result in more thorough
-// or accurate responses at the cost of increased latency.
-//
-// Returns an error if the API call fails or if the response is invalid.
-func (c *Client) CreateCompletion(prompt string, reasoningEffort string) (string, error) {
+// CreateCompletion implements Backend for the OpenAI client. It resolves the
+// model from opts.ModelID (falling back to pkg1.GetModel()) and forwards
+// opts.ReasoningEffort the same way CreateCompletionWithUsage does.
+func (c *Client) CreateCompletion(ctx context.Context, prompt string, opts CompletionOptions) (*CompletionResult, error) {
+	model := opts.ModelID
+	if model == "" {
+		model = pkg1.GetModel()
+	}
+
+	resp, err := c.api.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: prompt,
+				},
+			},
+			ReasoningEffort: opts.ReasoningEffort,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("chat completion error: %v", err)
+	}
+
+	usage := TokenUsage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	}
+
+	return &CompletionResult{
+		Output:       resp.Choices[0].Message.Content,
+		TokenUsage:   usage,
+		FinishReason: string(resp.Choices[0].FinishReason),
+		Cached:       false,
+		Cost:         estimateCost(resp.Model, usage),
+	}, nil
+}
+
+func init() {
+	RegisterBackendFactory("openai", func(config map[string]interface{}) (Backend, error) {
+		return &Client{
+			api: openai.NewClient(configString(config, "apiKey", os.Getenv("OPENAI_API_KEY"))),
+		}, nil
+	})
+}
+
+// CreateCompletionWithUsage generates a chat completion the same way as
+// CreateCompletion, but always uses pkg1.GetModel() and also fills in the
+// envelope's log probs, which CreateCompletion leaves unset.
+// ctx bounds the outbound OpenAI call, so a caller-configured timeout or an
+// interrupted eval actually cancels the in-flight request.
+func (c *Client) CreateCompletionWithUsage(ctx context.Context, prompt string, reasoningEffort string) (*CompletionResult, error) {
 	resp, err := c.api.CreateChatCompletion(
-		context.Background(),
+		ctx,
 		openai.ChatCompletionRequest{
 			Model: pkg1.GetModel(),
 			Messages: []openai.ChatCompletionMessage{
@@ -48,10 +102,188 @@ func (c *Client) CreateCompletion(prompt string, reasoningEffort string) (string
 			ReasoningEffort: reasoningEffort,
 		},
 	)
+	if err != nil {
+		return nil, fmt.Errorf("chat completion error: %v", err)
+	}
+
+	usage := TokenUsage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	}
 
+	var logProbs interface{}
+	if resp.Choices[0].LogProbs != nil {
+		logProbs = resp.Choices[0].LogProbs
+	}
+
+	return &CompletionResult{
+		Output:       resp.Choices[0].Message.Content,
+		TokenUsage:   usage,
+		FinishReason: string(resp.Choices[0].FinishReason),
+		Cached:       false,
+		Cost:         estimateCost(resp.Model, usage),
+		LogProbs:     logProbs,
+	}, nil
+}
+
+// CreateCompletionWithTools behaves like CreateCompletionWithUsage, but also sends
+// every tool registered via RegisterTool with the request. If the model comes back
+// with FinishReason "tool_calls", each call is resolved against the matching
+// registered Tool's Handler, the result is appended to the message history as a
+// tool message, and the model is re-invoked. This repeats until the model produces
+// a terminal (non tool-call) response. Every intermediate call is recorded in the
+// returned CompletionResult's ToolCalls, and its paired result in ToolMessages.
+// ctx bounds every chat completion request made during the loop. The loop is
+// capped at maxToolCallRounds so a model that never stops calling tools fails
+// with a clear error instead of running indefinitely.
+func (c *Client) CreateCompletionWithTools(ctx context.Context, prompt string, reasoningEffort string) (*CompletionResult, error) {
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: prompt,
+		},
+	}
+
+	var tools []openai.Tool
+	for _, tool := range registeredTools {
+		tools = append(tools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+
+	var toolCalls []ToolCall
+	var toolMessages []ToolMessage
+	var resp openai.ChatCompletionResponse
+
+	for round := 0; ; round++ {
+		if round >= maxToolCallRounds {
+			return nil, fmt.Errorf("tool call loop exceeded %d rounds without a terminal response", maxToolCallRounds)
+		}
+
+		var err error
+		resp, err = c.api.CreateChatCompletion(
+			ctx,
+			openai.ChatCompletionRequest{
+				Model:           pkg1.GetModel(),
+				Messages:        messages,
+				Tools:           tools,
+				ReasoningEffort: reasoningEffort,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("chat completion error: %v", err)
+		}
+
+		choice := resp.Choices[0]
+		if choice.FinishReason != openai.FinishReasonToolCalls {
+			break
+		}
+
+		messages = append(messages, choice.Message)
+		for _, call := range choice.Message.ToolCalls {
+			toolCalls = append(toolCalls, ToolCall{
+				ID:        call.ID,
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			})
+
+			result, err := resolveToolCall(call)
+			if err != nil {
+				return nil, fmt.Errorf("tool call error: %v", err)
+			}
+
+			toolMessages = append(toolMessages, ToolMessage{
+				ToolCallID: call.ID,
+				Name:       call.Function.Name,
+				Arguments:  call.Function.Arguments,
+				Result:     result,
+			})
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    result,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	usage := TokenUsage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	}
+
+	return &CompletionResult{
+		Output:       resp.Choices[0].Message.Content,
+		TokenUsage:   usage,
+		FinishReason: string(resp.Choices[0].FinishReason),
+		ToolCalls:    toolCalls,
+		ToolMessages: toolMessages,
+		Cached:       false,
+		Cost:         estimateCost(resp.Model, usage),
+	}, nil
+}
+
+// resolveToolCall looks up the registered Tool matching call's name and invokes
+// its Handler with the model-supplied arguments.
+func resolveToolCall(call openai.ToolCall) (string, error) {
+	tool, ok := registeredTools[call.Function.Name]
+	if !ok {
+		return "", fmt.Errorf("no tool registered for %q", call.Function.Name)
+	}
+	return tool.Handler(json.RawMessage(call.Function.Arguments))
+}
+
+// CreateCompletionStream generates a chat completion the same way as CreateCompletion,
+// but forwards each delta the model produces to out as soon as it arrives instead of
+// waiting for the full response. The final value pushed to out carries the aggregated
+// output so callers don't need to reassemble it themselves. ctx bounds the underlying
+// stream, so it's torn down as soon as a configured timeout or interrupt fires.
+func (c *Client) CreateCompletionStream(ctx context.Context, prompt string, reasoningEffort string, out chan<- map[string]interface{}) error {
+	stream, err := c.api.CreateChatCompletionStream(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: pkg1.GetModel(),
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: prompt,
+				},
+			},
+			ReasoningEffort: reasoningEffort,
+		},
+	)
 	if err != nil {
-		return "", fmt.Errorf("chat completion error: %v", err)
+		return fmt.Errorf("chat completion stream error: %v", err)
 	}
+	defer stream.Close()
 
-	return resp.Choices[0].Message.Content, nil
+	var full strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("chat completion stream recv error: %v", err)
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		full.WriteString(delta)
+		out <- map[string]interface{}{
+			"delta": delta,
+			"done":  false,
+		}
+	}
+
+	out <- map[string]interface{}{
+		"done":   true,
+		"output": full.String(),
+	}
+	return nil
 }
@@ -0,0 +1,51 @@
+package core
+
+// TokenUsage mirrors the token accounting OpenAI returns on every chat completion
+// response, so promptfoo can enforce token-budget assertions against Go providers
+// the same way it does for the built-in OpenAI provider.
+type TokenUsage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// ToolCall is one function/tool invocation the model asked for.
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// CompletionResult is the full envelope a provider hands back to promptfoo: the
+// text output plus everything needed for cost, token-budget, and finish-reason
+// assertions to work against a Go provider. This struct and the CallApi JSON it
+// marshals into are this package's half of that contract; mapping these fields
+// onto promptfoo's ProviderResponse so its assertions actually read them is
+// done by promptfoo's Node provider loader, outside this module.
+type CompletionResult struct {
+	Output       string        `json:"output"`
+	TokenUsage   TokenUsage    `json:"tokenUsage"`
+	FinishReason string        `json:"finishReason"`
+	ToolCalls    []ToolCall    `json:"toolCalls,omitempty"`
+	ToolMessages []ToolMessage `json:"toolMessages,omitempty"`
+	Cached       bool          `json:"cached"`
+	Cost         float64       `json:"cost"`
+	LogProbs     interface{}   `json:"logProbs,omitempty"`
+}
+
+// costPerMillionTokens holds {prompt, completion} USD prices per million tokens
+// for the models this package knows how to call. Models not listed here cost 0.
+var costPerMillionTokens = map[string][2]float64{
+	"o3-mini": {1.10, 4.40},
+	"gpt-4":   {30.00, 60.00},
+}
+
+// estimateCost returns the USD cost of a completion given its model and token
+// usage, or 0 if the model's pricing isn't known.
+func estimateCost(model string, usage TokenUsage) float64 {
+	prices, ok := costPerMillionTokens[model]
+	if !ok {
+		return 0
+	}
+	return float64(usage.PromptTokens)*prices[0]/1_000_000 + float64(usage.CompletionTokens)*prices[1]/1_000_000
+}
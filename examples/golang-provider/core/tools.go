@@ -0,0 +1,39 @@
+package core
+
+import "encoding/json"
+
+// ToolHandler resolves a single tool call against local Go code. args is the raw
+// JSON the model produced for the tool's parameters.
+type ToolHandler func(args json.RawMessage) (string, error)
+
+// Tool is a function/tool a provider exposes to the model. Parameters is the
+// JSON-schema describing its arguments, matching the shape OpenAI's tools API
+// expects.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage
+	Handler     ToolHandler
+}
+
+// registeredTools holds every Tool registered via RegisterTool, keyed by name.
+var registeredTools = map[string]Tool{}
+
+// RegisterTool makes a tool available to every subsequent CreateCompletionWithTools
+// call; the other Client completion methods don't send tools.
+// Providers call this from init(), before the client handles any prompts, so each
+// request's Tools list reflects everything that's been registered.
+func RegisterTool(tool Tool) {
+	registeredTools[tool.Name] = tool
+}
+
+// ToolMessage records one tool call the model made and the result it got back.
+// CompletionResult carries these out as JSON; whether promptfoo's assertions can
+// actually inspect them depends on its Node provider loader reading the
+// toolMessages field, which this module doesn't contain.
+type ToolMessage struct {
+	ToolCallID string `json:"toolCallId"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"`
+	Result     string `json:"result"`
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/promptfoo/promptfoo/examples/golang-provider/core"
@@ -15,26 +16,33 @@ var client = core.NewClient()
 
 // handlePrompt processes a prompt with configurable reasoning effort.
 // It accepts:
+//   - ctx: deadline/cancellation derived from the configured timeout, a --serve
+//     shutdown, or SIGTERM/SIGINT; forwarded straight through to the OpenAI call
 //   - prompt: the input text to send to the model
 //   - options: configuration map containing reasoning_effort setting
-//   - ctx: additional context (currently unused)
+//   - providerCtx: additional eval-row context (currently unused)
 //
-// Returns a map containing the "output" key with the model's response,
-// or an error if the API call fails.
-func handlePrompt(prompt string, options map[string]interface{}, ctx map[string]interface{}) (map[string]interface{}, error) {
+// Returns a map containing the "output" key with the model's response, along
+// with tokenUsage, finishReason, cached, and cost, or an error if the API call
+// fails.
+func handlePrompt(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
 	// Get reasoning_effort from config, default to pkg1's default if not specified
 	reasoningEffort := pkg1.GetDefaultReasoningEffort()
 	if mode, ok := options["config"].(map[string]interface{})["reasoning_effort"].(string); ok {
 		reasoningEffort = mode
 	}
 
-	output, err := client.CreateCompletion(prompt, reasoningEffort)
+	result, err := client.CreateCompletionWithUsage(ctx, prompt, reasoningEffort)
 	if err != nil {
 		return nil, fmt.Errorf("completion error: %v", err)
 	}
 
 	return map[string]interface{}{
-		"output": output,
+		"output":       result.Output,
+		"tokenUsage":   result.TokenUsage,
+		"finishReason": result.FinishReason,
+		"cached":       result.Cached,
+		"cost":         result.Cost,
 	}, nil
 }
 
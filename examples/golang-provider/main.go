@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/promptfoo/promptfoo/examples/golang-provider/core"
@@ -15,25 +16,51 @@ var client = core.NewClient()
 
 // handlePrompt processes a prompt with configurable reasoning effort.
 // It extracts the reasoning_effort from options (defaulting to pkg1's default)
-// and calls the OpenAI API through the core client.
-func handlePrompt(prompt string, options map[string]interface{}, ctx map[string]interface{}) (map[string]interface{}, error) {
+// and calls the OpenAI API through the core client, returning the full
+// CompletionResult envelope so token usage, finish reason, and cost assertions
+// work the same way they do against promptfoo's built-in OpenAI provider. ctx is
+// forwarded straight through to the OpenAI call, so a configured timeoutMs, a
+// --serve shutdown, or SIGTERM/SIGINT actually cancels the in-flight request.
+func handlePrompt(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
 	reasoningEffort := pkg1.GetDefaultReasoningEffort()
 	if val, ok := options["config"].(map[string]interface{})["reasoning_effort"].(string); ok {
 		reasoningEffort = val
 	}
 
-	output, err := client.CreateCompletion(prompt, reasoningEffort)
+	result, err := client.CreateCompletionWithUsage(ctx, prompt, reasoningEffort)
 	if err != nil {
 		return nil, fmt.Errorf("completion error: %v", err)
 	}
 
 	return map[string]interface{}{
-		"output": output,
+		"output":       result.Output,
+		"tokenUsage":   result.TokenUsage,
+		"finishReason": result.FinishReason,
+		"toolCalls":    result.ToolCalls,
+		"cached":       result.Cached,
+		"cost":         result.Cost,
+		"logProbs":     result.LogProbs,
 	}, nil
 }
 
+// handlePromptStream is the streaming counterpart of handlePrompt. It forwards each
+// token delta to out as it's generated so promptfoo can render progressive output.
+// ctx is forwarded straight through to the underlying stream, same as handlePrompt.
+func handlePromptStream(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}, out chan<- map[string]interface{}) error {
+	reasoningEffort := pkg1.GetDefaultReasoningEffort()
+	if val, ok := options["config"].(map[string]interface{})["reasoning_effort"].(string); ok {
+		reasoningEffort = val
+	}
+
+	if err := client.CreateCompletionStream(ctx, prompt, reasoningEffort, out); err != nil {
+		return fmt.Errorf("completion stream error: %v", err)
+	}
+	return nil
+}
+
 func init() {
 	// Assign our implementation to the wrapper's CallApi function.
 	// This makes it available to promptfoo for evaluation.
 	CallApi = handlePrompt
+	CallApiStream = handlePromptStream
 }
@@ -0,0 +1,46 @@
+// Package main implements a single promptfoo provider binary that can serve
+// OpenAI, IBM watsonx.ai, or HuggingFace Inference depending on its config, so
+// one compiled binary can back several `providers:` entries.
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/promptfoo/promptfoo/examples/golang-provider/core"
+)
+
+// handlePrompt resolves a Backend from options["config"]["provider"] (defaulting
+// to "openai") and dispatches the prompt to it. The backend is re-resolved on
+// every call since config can vary row to row across test cases. ctx is
+// forwarded straight through to the backend's outbound call.
+func handlePrompt(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
+	config, _ := options["config"].(map[string]interface{})
+
+	backend, err := core.NewBackend(config)
+	if err != nil {
+		return nil, fmt.Errorf("backend error: %v", err)
+	}
+
+	modelID, _ := config["modelId"].(string)
+	result, err := backend.CreateCompletion(ctx, prompt, core.CompletionOptions{
+		ModelID: modelID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("completion error: %v", err)
+	}
+
+	return map[string]interface{}{
+		"output":       result.Output,
+		"tokenUsage":   result.TokenUsage,
+		"finishReason": result.FinishReason,
+		"cached":       result.Cached,
+		"cost":         result.Cost,
+	}, nil
+}
+
+func init() {
+	// Assign our implementation to the wrapper's CallApi function.
+	// This makes it available to promptfoo for evaluation.
+	CallApi = handlePrompt
+}
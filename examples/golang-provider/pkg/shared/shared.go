@@ -13,8 +13,10 @@ func NewClient() *openai.Client {
 	return openai.NewClient(os.Getenv("OPENAI_API_KEY"))
 }
 
-// CreateCompletion creates a chat completion with the given prompt and temperature
-func CreateCompletion(client *openai.Client, prompt string, systemPrompt string, temperature float32) (string, error) {
+// CreateCompletion creates a chat completion with the given prompt and
+// temperature. ctx carries the caller's deadline/cancellation through to the
+// outbound request.
+func CreateCompletion(ctx context.Context, client *openai.Client, prompt string, systemPrompt string, temperature float32) (string, error) {
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleUser,
@@ -33,7 +35,7 @@ func CreateCompletion(client *openai.Client, prompt string, systemPrompt string,
 	}
 
 	resp, err := client.CreateChatCompletion(
-		context.Background(),
+		ctx,
 		openai.ChatCompletionRequest{
 			Model:       openai.GPT4,
 			Temperature: temperature,
@@ -46,4 +48,66 @@ func CreateCompletion(client *openai.Client, prompt string, systemPrompt string,
 	}
 
 	return resp.Choices[0].Message.Content, nil
+}
+
+// TokenUsage mirrors the token accounting OpenAI returns on every chat
+// completion response.
+type TokenUsage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// CompletionResult is the full envelope a provider hands back to promptfoo.
+type CompletionResult struct {
+	Output       string     `json:"output"`
+	TokenUsage   TokenUsage `json:"tokenUsage"`
+	FinishReason string     `json:"finishReason"`
+	Cached       bool       `json:"cached"`
+}
+
+// CreateCompletionWithUsage creates a chat completion the same way as
+// CreateCompletion, but returns the full CompletionResult envelope (token usage
+// and finish reason) instead of just the output text. ctx carries the same
+// deadline/cancellation as CreateCompletion's.
+func CreateCompletionWithUsage(ctx context.Context, client *openai.Client, prompt string, systemPrompt string, temperature float32) (*CompletionResult, error) {
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: prompt,
+		},
+	}
+
+	if systemPrompt != "" {
+		messages = append([]openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: systemPrompt,
+			},
+		}, messages...)
+	}
+
+	resp, err := client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model:       openai.GPT4,
+			Temperature: temperature,
+			Messages:    messages,
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("ChatCompletion error: %v", err)
+	}
+
+	return &CompletionResult{
+		Output: resp.Choices[0].Message.Content,
+		TokenUsage: TokenUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+		FinishReason: string(resp.Choices[0].FinishReason),
+		Cached:       false,
+	}, nil
 } 
\ No newline at end of file
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/promptfoo/promptfoo/examples/golang-provider/pkg/shared"
@@ -9,21 +10,25 @@ import (
 // Initialize OpenAI client
 var client = shared.NewClient()
 
-// handlePrompt implements the OpenAI API call with temperature control
-func handlePrompt(prompt string, options map[string]interface{}, ctx map[string]interface{}) (map[string]interface{}, error) {
+// handlePrompt implements the OpenAI API call with temperature control. ctx is
+// forwarded straight through to the outbound OpenAI call.
+func handlePrompt(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
 	// Get temperature from config, default to 0.7 if not specified
 	temperature := float32(0.7)
 	if temp, ok := options["config"].(map[string]interface{})["temperature"].(float64); ok {
 		temperature = float32(temp)
 	}
 
-	output, err := shared.CreateCompletion(client, prompt, "", temperature)
+	result, err := shared.CreateCompletionWithUsage(ctx, client, prompt, "", temperature)
 	if err != nil {
 		return nil, fmt.Errorf("completion error: %v", err)
 	}
 
 	return map[string]interface{}{
-		"output": output,
+		"output":       result.Output,
+		"tokenUsage":   result.TokenUsage,
+		"finishReason": result.FinishReason,
+		"cached":       result.Cached,
 	}, nil
 }
 
@@ -0,0 +1,77 @@
+// Package main implements a promptfoo provider that resolves OpenAI tool/function
+// calls against local Go functions before returning a final answer.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/promptfoo/promptfoo/examples/golang-provider/core"
+	"github.com/promptfoo/promptfoo/examples/golang-provider/pkg1"
+)
+
+// client is the shared OpenAI client instance used for all requests.
+var client = core.NewClient()
+
+// getWeatherArgs is the shape of the arguments the model supplies for get_weather.
+type getWeatherArgs struct {
+	Location string `json:"location"`
+}
+
+// getWeather is a stand-in for a real weather lookup, so the example has
+// something deterministic for the model to call.
+func getWeather(args json.RawMessage) (string, error) {
+	var parsed getWeatherArgs
+	if err := json.Unmarshal(args, &parsed); err != nil {
+		return "", fmt.Errorf("invalid get_weather arguments: %v", err)
+	}
+	return fmt.Sprintf("It's 72F and sunny in %s.", parsed.Location), nil
+}
+
+func init() {
+	core.RegisterTool(core.Tool{
+		Name:        "get_weather",
+		Description: "Get the current weather for a location",
+		Parameters: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"location": {"type": "string", "description": "City and state, e.g. San Francisco, CA"}
+			},
+			"required": ["location"]
+		}`),
+		Handler: getWeather,
+	})
+}
+
+// handlePrompt resolves any tool calls the model makes before returning the
+// final answer, with every intermediate call in "toolCalls" and its paired
+// result in "toolMessages". ctx is forwarded through every chat completion
+// request the tool-calling loop makes.
+func handlePrompt(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
+	reasoningEffort := pkg1.GetDefaultReasoningEffort()
+	if val, ok := options["config"].(map[string]interface{})["reasoning_effort"].(string); ok {
+		reasoningEffort = val
+	}
+
+	result, err := client.CreateCompletionWithTools(ctx, prompt, reasoningEffort)
+	if err != nil {
+		return nil, fmt.Errorf("completion error: %v", err)
+	}
+
+	return map[string]interface{}{
+		"output":       result.Output,
+		"tokenUsage":   result.TokenUsage,
+		"finishReason": result.FinishReason,
+		"toolCalls":    result.ToolCalls,
+		"toolMessages": result.ToolMessages,
+		"cached":       result.Cached,
+		"cost":         result.Cost,
+	}, nil
+}
+
+func init() {
+	// Assign our implementation to the wrapper's CallApi function.
+	// This makes it available to promptfoo for evaluation.
+	CallApi = handlePrompt
+}
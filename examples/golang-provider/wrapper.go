@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"reflect"
+	"syscall"
 )
 
 // CallApi is the provider's implementation of promptfoo's API interface.
@@ -12,22 +16,126 @@ import (
 //
 // The prompt parameter is the input text to send to the model.
 // The options parameter may contain a config map with a "reasoning_effort" key
-// that accepts "low", "medium", or "high" values.
+// that accepts "low", "medium", or "high" values. ctx carries the deadline
+// derived from options["config"]["timeoutMs"]/PROMPTFOO_PROVIDER_TIMEOUT (and,
+// in --serve mode, the connection's parent context); an implementation should
+// forward it to its outbound HTTP call so timeout, shutdown, and SIGTERM/SIGINT
+// actually cancel the in-flight request rather than just being abandoned.
 //
 // Returns a map containing the "output" key with the model's response,
 // or an error if the API call fails.
-type ApiFunc func(string, map[string]interface{}, map[string]interface{}) (map[string]interface{}, error)
+type ApiFunc func(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error)
 
 // Default implementation that will be replaced by the actual provider
-func defaultCallApi(prompt string, options map[string]interface{}, ctx map[string]interface{}) (map[string]interface{}, error) {
+func defaultCallApi(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
 	return nil, fmt.Errorf("CallApi not implemented")
 }
 
 var CallApi ApiFunc = defaultCallApi
 
+// CallApiStreamFunc is implemented by providers that want to emit their response
+// incrementally instead of returning it all at once. Each map pushed onto out is
+// written to stdout as a single line of newline-delimited JSON, so the Node side
+// can read it line-by-line and forward chunks as they arrive. Implementations
+// should push partial {"delta": "...", "done": false} chunks followed by a final
+// chunk with "done": true carrying the aggregated "output" (and any usage info).
+// ctx carries the same deadline/cancellation as ApiFunc's.
+//
+// This package only produces that NDJSON stream; the stdout reader that forwards
+// it through promptfoo's streaming-callback interface lives in promptfoo's Node
+// provider loader, outside this module, and isn't part of this change.
+type CallApiStreamFunc func(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}, out chan<- map[string]interface{}) error
+
+// Default implementation that will be replaced by the actual provider
+func defaultCallApiStream(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}, out chan<- map[string]interface{}) error {
+	return fmt.Errorf("CallApiStream not implemented")
+}
+
+var CallApiStream CallApiStreamFunc = defaultCallApiStream
+
+// callResult carries the outcome of a reflect.Value.Call invocation made on its
+// own goroutine, so main can select between it finishing and ctx expiring.
+type callResult struct {
+	values []reflect.Value
+}
+
+// runReflectCall invokes f on its own goroutine with the given arguments and
+// returns as soon as either it finishes or ctx is done (deadline exceeded or the
+// process was signaled), whichever comes first. A timed-out or canceled call
+// leaks its goroutine, which is acceptable here since the process exits
+// immediately after.
+func runReflectCall(ctx context.Context, f reflect.Value, in []reflect.Value) ([]reflect.Value, error) {
+	resultCh := make(chan callResult, 1)
+	go func() {
+		resultCh <- callResult{values: f.Call(in)}
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result.values, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("provider call canceled: %v", ctx.Err())
+	}
+}
+
+// runStream drives a CallApiStreamFunc, writing each chunk it produces to stdout
+// as one line of JSON as soon as it's available. If ctx is done before the
+// provider finishes, any chunk already written is flushed and a "stream
+// canceled" error carrying ctx.Err()'s message is returned, so an interrupted eval doesn't leave a half-written line on stdout.
+func runStream(ctx context.Context, fn CallApiStreamFunc, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) error {
+	out := make(chan map[string]interface{})
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn(ctx, prompt, options, providerCtx, out)
+		close(out)
+	}()
+
+	writer := bufio.NewWriter(os.Stdout)
+	for {
+		select {
+		case chunk, ok := <-out:
+			if !ok {
+				return <-done
+			}
+			line, err := json.Marshal(chunk)
+			if err != nil {
+				return fmt.Errorf("error marshaling stream chunk: %v", err)
+			}
+			if _, err := writer.Write(append(line, '\n')); err != nil {
+				return fmt.Errorf("error writing stream chunk: %v", err)
+			}
+			if err := writer.Flush(); err != nil {
+				return fmt.Errorf("error flushing stream chunk: %v", err)
+			}
+		case <-ctx.Done():
+			writer.Flush()
+			return fmt.Errorf("stream canceled: %v", ctx.Err())
+		}
+	}
+}
+
 func main() {
+	// Cancel the root context on SIGTERM/SIGINT so an eval that promptfoo
+	// interrupts doesn't leak this process or leave a half-written stdout line.
+	// Unlike this package's streaming/--serve/envelope/tool-calling features,
+	// deadline/cancellation propagation is entirely a Go-side contract (CallApi's
+	// ctx argument, timeoutMs/PROMPTFOO_PROVIDER_TIMEOUT, this signal handler) and
+	// needs no corresponding promptfoo Node-side change.
+	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) == 3 && os.Args[1] == "--serve" {
+		if err := runServe(rootCtx, os.Args[2]); err != nil {
+			fmt.Printf("Error serving: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(os.Args) != 4 {
 		fmt.Println("Usage: golang_wrapper <script_path> <function_name> <json_args>")
+		fmt.Println("       golang_wrapper --serve <socket_path>")
 		os.Exit(1)
 	}
 
@@ -42,6 +150,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	if functionName == "call_api_stream" || functionName == "CallApiStream" {
+		prompt, _ := args[0].(string)
+		options, _ := args[1].(map[string]interface{})
+		providerCtx, _ := args[2].(map[string]interface{})
+
+		ctx, cancel := withConfiguredTimeout(rootCtx, options)
+		defer cancel()
+
+		if err := runStream(ctx, CallApiStream, prompt, options, providerCtx); err != nil {
+			fmt.Printf("Error calling function: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Get the function by name using reflection
 	f := reflect.ValueOf(nil)
 	switch functionName {
@@ -52,12 +175,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	options, _ := args[1].(map[string]interface{})
+	ctx, cancel := withConfiguredTimeout(rootCtx, options)
+	defer cancel()
+
 	// Call the function
-	result := f.Call([]reflect.Value{
+	result, err := runReflectCall(ctx, f, []reflect.Value{
+		reflect.ValueOf(ctx),
 		reflect.ValueOf(args[0].(string)),
 		reflect.ValueOf(args[1]),
 		reflect.ValueOf(args[2]),
 	})
+	if err != nil {
+		fmt.Printf("Error calling function: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Check for errors
 	if !result[1].IsNil() {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+)
+
+func TestHandleServeConnCallApi(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go handleServeConn(context.Background(), server, nil)
+
+	req, err := json.Marshal(serveRequest{
+		Function: "call_api",
+		Args: []json.RawMessage{
+			json.RawMessage(`"hello"`),
+			json.RawMessage(`{}`),
+			json.RawMessage(`{}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error marshaling request: %v", err)
+	}
+
+	if _, err := client.Write(append(req, '\n')); err != nil {
+		t.Fatalf("error writing request: %v", err)
+	}
+
+	line, err := bufio.NewReader(client).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		t.Fatalf("error unmarshaling response: %v, raw: %q", err, line)
+	}
+
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a result, got %+v", resp)
+	}
+	if output, ok := result["output"].(string); !ok || output != "hello" {
+		t.Errorf("got output %q, want %q", output, "hello")
+	}
+}
+
+func TestHandleServeConnUnknownFunction(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go handleServeConn(context.Background(), server, nil)
+
+	req, _ := json.Marshal(serveRequest{Function: "does_not_exist"})
+	if _, err := client.Write(append(req, '\n')); err != nil {
+		t.Fatalf("error writing request: %v", err)
+	}
+
+	line, err := bufio.NewReader(client).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		t.Fatalf("error unmarshaling response: %v, raw: %q", err, line)
+	}
+	if _, ok := resp["error"]; !ok {
+		t.Errorf("expected an error for an unknown function, got %+v", resp)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+)
+
+// timeoutEnvVar lets operators set a default per-call timeout without touching
+// every provider's config.
+const timeoutEnvVar = "PROMPTFOO_PROVIDER_TIMEOUT"
+
+// resolveTimeout returns the duration a single CallApi/CallApiStream invocation
+// should be allowed to run for, checking options["config"]["timeoutMs"] first
+// and falling back to the PROMPTFOO_PROVIDER_TIMEOUT environment variable
+// (also in milliseconds). Returns 0 if neither is set, meaning no timeout.
+func resolveTimeout(options map[string]interface{}) time.Duration {
+	if config, ok := options["config"].(map[string]interface{}); ok {
+		switch timeoutMs := config["timeoutMs"].(type) {
+		case float64:
+			return time.Duration(timeoutMs) * time.Millisecond
+		case string:
+			if ms, err := strconv.ParseFloat(timeoutMs, 64); err == nil {
+				return time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	if envMs := os.Getenv(timeoutEnvVar); envMs != "" {
+		if ms, err := strconv.ParseFloat(envMs, 64); err == nil {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	return 0
+}
+
+// withConfiguredTimeout derives a child context from parent that's bounded by
+// resolveTimeout(options), or parent unchanged (with a no-op cancel) if no
+// timeout is configured.
+func withConfiguredTimeout(parent context.Context, options map[string]interface{}) (context.Context, context.CancelFunc) {
+	timeout := resolveTimeout(options)
+	if timeout <= 0 {
+		return parent, func() {}
+	}
+	return context.WithTimeout(parent, timeout)
+}
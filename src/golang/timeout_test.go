@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestResolveTimeout(t *testing.T) {
+	t.Run("from config", func(t *testing.T) {
+		options := map[string]interface{}{
+			"config": map[string]interface{}{"timeoutMs": float64(1500)},
+		}
+		if got := resolveTimeout(options); got != 1500*time.Millisecond {
+			t.Errorf("got %v, want %v", got, 1500*time.Millisecond)
+		}
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		os.Setenv(timeoutEnvVar, "2000")
+		defer os.Unsetenv(timeoutEnvVar)
+
+		if got := resolveTimeout(map[string]interface{}{}); got != 2000*time.Millisecond {
+			t.Errorf("got %v, want %v", got, 2000*time.Millisecond)
+		}
+	})
+
+	t.Run("config takes precedence over env", func(t *testing.T) {
+		os.Setenv(timeoutEnvVar, "2000")
+		defer os.Unsetenv(timeoutEnvVar)
+
+		options := map[string]interface{}{
+			"config": map[string]interface{}{"timeoutMs": float64(500)},
+		}
+		if got := resolveTimeout(options); got != 500*time.Millisecond {
+			t.Errorf("got %v, want %v", got, 500*time.Millisecond)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		if got := resolveTimeout(map[string]interface{}{}); got != 0 {
+			t.Errorf("got %v, want 0", got)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,20 +18,37 @@ var (
 )
 
 // Mock function that matches the expected signature
-func mockCallApi(prompt string, options map[string]interface{}, ctx map[string]interface{}) (map[string]interface{}, error) {
+func mockCallApi(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"output": prompt,
 		"debug": map[string]interface{}{
 			"raw_prompt": prompt,
 			"options":    options,
-			"context":    ctx,
+			"context":    providerCtx,
 		},
 	}, nil
 }
 
+// mockCallApiStream emits the prompt one character at a time, then a final chunk
+// carrying the aggregated output.
+func mockCallApiStream(ctx context.Context, prompt string, options map[string]interface{}, providerCtx map[string]interface{}, out chan<- map[string]interface{}) error {
+	for _, r := range prompt {
+		out <- map[string]interface{}{
+			"delta": string(r),
+			"done":  false,
+		}
+	}
+	out <- map[string]interface{}{
+		"done":   true,
+		"output": prompt,
+	}
+	return nil
+}
+
 func TestMain(m *testing.M) {
-	// Replace the real function with mock one
+	// Replace the real functions with mock ones
 	CallApi = mockCallApi
+	CallApiStream = mockCallApiStream
 	os.Exit(m.Run())
 }
 
@@ -110,6 +128,10 @@ func TestHelperProcess(t *testing.T) {
 	// Set up os.Args for main()
 	os.Args = append([]string{args[0]}, args...)
 	main()
+	// main() only os.Exit()s on error; on success, exit here too so the test
+	// binary doesn't fall through to its normal "--- PASS" trailer, which would
+	// otherwise land on stdout as an extra, non-JSON line.
+	os.Exit(0)
 }
 
 func TestWrapper(t *testing.T) {
@@ -229,3 +251,60 @@ func TestWrapper(t *testing.T) {
 		})
 	}
 }
+
+// runStreamWrapper runs the wrapper the same way runWrapper does, but returns every
+// line of stdout so callers can inspect the full sequence of streamed chunks.
+func runStreamWrapper(t *testing.T, args []string) ([]map[string]interface{}, error) {
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestHelperProcess", "--"}, args...)...)
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("wrapper error: %v, stderr: %q", err, stderr.String())
+	}
+
+	var chunks []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var chunk map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return nil, fmt.Errorf("json unmarshal error: %v, raw line: %q", err, line)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+func TestStreamWrapper(t *testing.T) {
+	chunks, err := runStreamWrapper(t, []string{"script.go", "call_api_stream", `["hi",{},{}]`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks (2 deltas + final), got %d: %+v", len(chunks), chunks)
+	}
+
+	for i, want := range []string{"h", "i"} {
+		if delta, ok := chunks[i]["delta"].(string); !ok || delta != want {
+			t.Errorf("chunk %d: got delta %q, want %q", i, delta, want)
+		}
+		if done, ok := chunks[i]["done"].(bool); !ok || done {
+			t.Errorf("chunk %d: expected done=false", i)
+		}
+	}
+
+	final := chunks[len(chunks)-1]
+	if done, ok := final["done"].(bool); !ok || !done {
+		t.Error("final chunk: expected done=true")
+	}
+	if output, ok := final["output"].(string); !ok || output != "hi" {
+		t.Errorf("final chunk: got output %q, want %q", output, "hi")
+	}
+}